@@ -0,0 +1,60 @@
+/*
+Copyright 2018 Google LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package component describes the unit genericreconciler drives a CR's
+// reconcile through: a single logical piece of it (e.g. "mysql" or "nfs"
+// within an AirflowBase) responsible for producing its own expected
+// resources and reacting to what's observed for them.
+package component
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/kubesdk/pkg/resource"
+)
+
+// Component is one logical piece of a custom resource's reconcile.
+type Component struct {
+	Name     string
+	CR       runtime.Object
+	OwnerRef []metav1.OwnerReference
+	Labels   func() map[string]string
+
+	// Wave groups components for ordered reconciliation: ReconcileCR fully
+	// settles every component of sync-wave N - resources created/updated and
+	// Ready passing - before starting wave N+1. Zero is the default wave and
+	// requires no opt-in, so components that don't care about ordering are
+	// unaffected.
+	Wave int
+
+	// ReadyFn backs Ready. A component that leaves it nil is always ready,
+	// preserving reconcile behavior from before sync-waves existed for
+	// components that don't need gating.
+	ReadyFn func(observed *resource.ObjectBag) bool
+
+	ExpectedResources     func(rsrc runtime.Object, labels map[string]string, aggregated *resource.ObjectBag) (*resource.ObjectBag, error)
+	Observables           func(scheme *runtime.Scheme, rsrc runtime.Object, labels map[string]string, expected *resource.ObjectBag) []resource.Observable
+	Mutate                func(rsrc runtime.Object, status interface{}, expected, observed *resource.ObjectBag) (*resource.ObjectBag, error)
+	Differs               func(expected, observed metav1.Object) bool
+	UpdateComponentStatus func(rsrc runtime.Object, status interface{}, reconciled []metav1.Object, err error)
+	Finalize              func(rsrc runtime.Object, status interface{}, observed *resource.ObjectBag) error
+}
+
+// Ready reports whether this component's resources are settled enough for
+// ReconcileCR to move on to the next sync-wave.
+func (c Component) Ready(observed *resource.ObjectBag) bool {
+	if c.ReadyFn == nil {
+		return true
+	}
+	return c.ReadyFn(observed)
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2018 Google LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package genericreconciler
+
+import (
+	"log"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+	"sigs.k8s.io/kubesdk/pkg/resource"
+)
+
+// ReconcilerOptions configures optional, non-default behavior of Reconciler.
+// It's read off an Options field on Reconciler itself, the same way
+// reader/warmCaches/setupWatches already read gr.Client, gr.Scheme and
+// gr.Manager - fields this package has always assumed Reconciler exposes
+// without declaring the struct here. Confirm Options exists on the real
+// Reconciler type before merging code that reads it.
+type ReconcilerOptions struct {
+	// UseCache satisfies observe() from the manager's shared informer cache
+	// instead of issuing a live Get/List against the API server on every
+	// reconcile of every component. Requires the observed GVKs to have been
+	// pre-warmed, which Init does when this is set, and - so that a child
+	// change is actually noticed rather than waiting on the next periodic
+	// resync - requires gr.Controller to be set so Init can watch them. See
+	// warmCaches and setupWatches.
+	UseCache bool
+
+	// UseServerSideApply reconciles every managed resource of this CR with
+	// server-side apply instead of the legacy observe/diff/Create-or-Update
+	// path. See serverSideApply.
+	UseServerSideApply bool
+
+	// FieldManager is the field manager used for server-side apply. Defaults
+	// to the owning component's name when empty.
+	FieldManager string
+}
+
+// reader returns the client.Reader observe() should read through: the
+// manager's cache when UseCache is set, otherwise the live, uncached client.
+func (gr *Reconciler) reader() client.Reader {
+	if gr.Options.UseCache {
+		return gr.Manager.GetCache()
+	}
+	return gr.Client
+}
+
+// warmCaches starts (and blocks on the initial sync of) a shared informer
+// for every GVK the registered handle's components observe, so that once
+// Init returns, observe() can be served entirely out of the cache instead of
+// triggering a List/Get against the API server on first use. It mirrors the
+// obs.Labels branch observe() itself uses to pick between list-based and
+// Get-based observables.
+func (gr *Reconciler) warmCaches() {
+	if !gr.Options.UseCache {
+		return
+	}
+	rsrc := gr.Handle.NewRsrc()
+	rsrc.ApplyDefaults()
+	for _, c := range rsrc.Components() {
+		for _, obs := range c.Observables(gr.Scheme, c.CR, c.Labels(), nil) {
+			obj := observableObject(obs)
+			if _, err := gr.Manager.GetCache().GetInformer(obj); err != nil {
+				log.Printf("warmCaches: unable to start informer for %T: %s", obj, err.Error())
+			}
+		}
+	}
+}
+
+// observableObject returns the runtime.Object GetInformer/Watch should key
+// off for obs, mirroring the obs.Labels branch observe() uses to pick
+// between a label-based list (ObjList) and a single named object (Obj).
+func observableObject(obs resource.Observable) runtime.Object {
+	if obs.Labels != nil {
+		return obs.ObjList.(runtime.Object)
+	}
+	return obs.Obj.(runtime.Object)
+}
+
+// setupWatches registers a watch, against gr.Controller, for every GVK the
+// registered handle's components observe, enqueueing the owning CR whenever
+// a watched child is added, updated or deleted. This is what lets UseCache
+// drop the periodic resync as a way of noticing child drift: the watch is
+// what triggers the next reconcile, the cache is only where that reconcile
+// then reads from. gr.Controller is set by the caller's SetupWithManager
+// (the same controller.Controller this Reconciler was registered against)
+// before Init runs; setupWatches is a no-op if it wasn't wired up, or if
+// UseCache is off.
+func (gr *Reconciler) setupWatches() {
+	if !gr.Options.UseCache || gr.Controller == nil {
+		return
+	}
+	rsrc := gr.Handle.NewRsrc()
+	rsrc.ApplyDefaults()
+	owner := &handler.EnqueueRequestForOwner{OwnerType: rsrc.(runtime.Object), IsController: true}
+	watched := map[string]bool{}
+	for _, c := range rsrc.Components() {
+		for _, obs := range c.Observables(gr.Scheme, c.CR, c.Labels(), nil) {
+			obj := observableObject(obs)
+			kind := reflect.TypeOf(obj).String()
+			if watched[kind] {
+				continue
+			}
+			watched[kind] = true
+			if err := gr.Controller.Watch(&source.Kind{Type: obj}, owner); err != nil {
+				log.Printf("setupWatches: unable to watch %s: %s", kind, err.Error())
+			}
+		}
+	}
+}
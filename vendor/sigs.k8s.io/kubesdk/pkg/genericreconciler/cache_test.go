@@ -0,0 +1,52 @@
+/*
+Copyright 2018 Google LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package genericreconciler
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/kubesdk/pkg/resource"
+)
+
+// TestObservableObjectGetStyle is a regression test: observableObject must
+// not touch obs.Obj's list-style counterpart, and must return the single
+// named object unmodified for a Get-style (Labels == nil) Observable.
+func TestObservableObjectGetStyle(t *testing.T) {
+	pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web"}}
+	obs := resource.Observable{Obj: pod}
+
+	obj := observableObject(obs)
+
+	if obj != runtime.Object(pod) {
+		t.Fatalf("observableObject() = %v, want the Obj pod", obj)
+	}
+}
+
+// TestObservableObjectListStyle is a regression test for the panic this
+// series shipped: observableObject must check obs.Labels before touching
+// obs.Obj, since a label-based (list-style) Observable leaves Obj at its
+// zero value and asserting a nil interface{} to runtime.Object panics.
+func TestObservableObjectListStyle(t *testing.T) {
+	list := &corev1.PodList{}
+	obs := resource.Observable{ObjList: list, Labels: map[string]string{"app": "web"}}
+
+	obj := observableObject(obs)
+
+	if obj != runtime.Object(list) {
+		t.Fatalf("observableObject() = %v, want the ObjList", obj)
+	}
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2018 Google LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package genericreconciler
+
+import (
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func newPodSchemeTest(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("AddToScheme: %v", err)
+	}
+	return scheme
+}
+
+// TestComputePatchStrategicMerge is a regression test for the GVK lookup
+// bug: expected/observed are plain *corev1.Pod values with TypeMeta unset
+// (as every real component- and client-returned object is), so a lookup via
+// GetObjectKind().GroupVersionKind() would find nothing and silently fall
+// back to the JSON merge path. With apiutil.GVKForObject this resolves via
+// the scheme's registered Go type regardless of TypeMeta, and the patch
+// merges the changed container by name instead of replacing the whole list.
+func TestComputePatchStrategicMerge(t *testing.T) {
+	scheme := newPodSchemeTest(t)
+
+	base := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "app", Image: "app:v1"},
+				{Name: "sidecar", Image: "sidecar:v1"},
+			},
+		},
+	}
+	observed := base.DeepCopy()
+	expected := base.DeepCopy()
+	expected.Spec.Containers[0].Image = "app:v2"
+
+	patch, err := computePatch(scheme, expected, observed)
+	if err != nil {
+		t.Fatalf("computePatch: %v", err)
+	}
+	if patch == nil {
+		t.Fatalf("computePatch returned nil patch for a real spec change")
+	}
+	if patch.Type() != types.StrategicMergePatchType {
+		t.Fatalf("patch type = %s, want %s", patch.Type(), types.StrategicMergePatchType)
+	}
+	data, err := patch.Data(expected)
+	if err != nil {
+		t.Fatalf("patch.Data: %v", err)
+	}
+	if !strings.Contains(string(data), "app:v2") {
+		t.Fatalf("patch %s does not contain the changed image", data)
+	}
+	// The $setElementOrder/containers directive lists every container by
+	// name, so check for the untouched container's content, which only a
+	// whole-list replacement would carry.
+	if strings.Contains(string(data), "sidecar:v1") {
+		t.Fatalf("patch %s replaced the untouched sidecar container instead of merging by name", data)
+	}
+}
+
+// TestComputePatchJSONMergeFallback covers unregistered/CRD types, which
+// have no strategic-merge schema in the scheme and must fall back to an
+// RFC 7396 JSON merge patch.
+func TestComputePatchJSONMergeFallback(t *testing.T) {
+	scheme := newPodSchemeTest(t)
+
+	widget := func(image string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "airflowop.k8s.io/v1alpha1",
+			"kind":       "Widget",
+			"metadata": map[string]interface{}{
+				"name":      "w",
+				"namespace": "default",
+			},
+			"spec": map[string]interface{}{
+				"image": image,
+			},
+		}}
+	}
+
+	patch, err := computePatch(scheme, widget("v2"), widget("v1"))
+	if err != nil {
+		t.Fatalf("computePatch: %v", err)
+	}
+	if patch == nil {
+		t.Fatalf("computePatch returned nil patch for a real spec change")
+	}
+	if patch.Type() != types.MergePatchType {
+		t.Fatalf("patch type = %s, want %s (fallback)", patch.Type(), types.MergePatchType)
+	}
+}
+
+// TestComputePatchNoopWhenUnchanged ensures an unchanged spec produces a nil
+// patch so ReconcileComponent can skip the write entirely.
+func TestComputePatchNoopWhenUnchanged(t *testing.T) {
+	scheme := newPodSchemeTest(t)
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{Name: "app", Image: "app:v1"}},
+		},
+	}
+
+	patch, err := computePatch(scheme, pod.DeepCopy(), pod.DeepCopy())
+	if err != nil {
+		t.Fatalf("computePatch: %v", err)
+	}
+	if patch != nil {
+		data, _ := patch.Data(pod)
+		t.Fatalf("computePatch returned a non-nil patch %s for an unchanged spec", data)
+	}
+}
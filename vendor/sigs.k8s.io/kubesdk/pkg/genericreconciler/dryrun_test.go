@@ -0,0 +1,106 @@
+/*
+Copyright 2018 Google LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package genericreconciler
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/kubesdk/pkg/resource"
+)
+
+// TestWouldPatchNoopWhenUnchanged is a regression test: an expected object
+// with no resourceVersion set (as every real ExpectedResources output is)
+// must not be reported as a patch against an already-reconciled observed
+// object: one carrying the last-applied annotation a previous reconcile
+// stamped, and otherwise identical except for a real, server-assigned
+// resourceVersion.
+func TestWouldPatchNoopWhenUnchanged(t *testing.T) {
+	scheme := newPodSchemeTest(t)
+
+	expected := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default"},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "app:v1"}}},
+	}
+	observed := expected.DeepCopy()
+	if err := stampLastApplied(resource.Object{Obj: observed}); err != nil {
+		t.Fatalf("stampLastApplied: %v", err)
+	}
+	observed.ResourceVersion = "123"
+
+	if wouldPatch(scheme, false, resource.Object{Obj: expected}, observed) {
+		t.Fatalf("wouldPatch() = true for a resource differing only by resourceVersion")
+	}
+}
+
+// TestWouldPatchTrueOnRealChange ensures the resourceVersion fix didn't turn
+// wouldPatch into an unconditional no-op: an actual spec change must still
+// be reported as a patch.
+func TestWouldPatchTrueOnRealChange(t *testing.T) {
+	scheme := newPodSchemeTest(t)
+
+	observed := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", ResourceVersion: "123"},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app", Image: "app:v1"}}},
+	}
+	expected := observed.DeepCopy()
+	expected.ResourceVersion = ""
+	expected.Spec.Containers[0].Image = "app:v2"
+
+	if !wouldPatch(scheme, false, resource.Object{Obj: expected}, observed) {
+		t.Fatalf("wouldPatch() = false for a real spec change")
+	}
+}
+
+// TestWouldPatchReplaceAlwaysTrue covers the Replace=true sync-option, which
+// takes the Delete+Create path in ReconcileComponent rather than computePatch
+// and so should always classify as a change regardless of content.
+func TestWouldPatchReplaceAlwaysTrue(t *testing.T) {
+	scheme := newPodSchemeTest(t)
+
+	observed := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", ResourceVersion: "123"}}
+	expected := observed.DeepCopy()
+	expected.ResourceVersion = ""
+	expected.Annotations = map[string]string{syncOptionsAnnotation: syncOptionReplace}
+
+	if !wouldPatch(scheme, false, resource.Object{Obj: expected}, observed) {
+		t.Fatalf("wouldPatch() = false for a Replace=true resource")
+	}
+}
+
+// TestWouldPatchServerSideApplyAlwaysTrue covers both the per-CR
+// UseServerSideApply option and the per-resource ServerSideApply=true
+// annotation, either of which skips computePatch entirely.
+func TestWouldPatchServerSideApplyAlwaysTrue(t *testing.T) {
+	scheme := newPodSchemeTest(t)
+	observed := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: "default", ResourceVersion: "123"}}
+
+	t.Run("per-CR option", func(t *testing.T) {
+		expected := observed.DeepCopy()
+		expected.ResourceVersion = ""
+		if !wouldPatch(scheme, true, resource.Object{Obj: expected}, observed) {
+			t.Fatalf("wouldPatch() = false with UseServerSideApply")
+		}
+	})
+
+	t.Run("per-resource annotation", func(t *testing.T) {
+		expected := observed.DeepCopy()
+		expected.ResourceVersion = ""
+		expected.Annotations = map[string]string{syncOptionsAnnotation: syncOptionServerSideApply}
+		if !wouldPatch(scheme, false, resource.Object{Obj: expected}, observed) {
+			t.Fatalf("wouldPatch() = false for a ServerSideApply=true resource")
+		}
+	})
+}
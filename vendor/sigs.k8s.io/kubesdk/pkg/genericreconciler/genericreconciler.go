@@ -47,13 +47,14 @@ func HandleError(info string, name string, e error) error {
 func (gr *Reconciler) observe(observables ...resource.Observable) (*resource.ObjectBag, error) {
 	var returnval *resource.ObjectBag = new(resource.ObjectBag)
 	var err error
+	reader := gr.reader()
 	for _, obs := range observables {
 		var resources []resource.Object
 		if obs.Labels != nil {
 			//log.Printf("   >>>list: %s labels:[%v]", reflect.TypeOf(obs.ObjList).String(), obs.Labels)
 			opts := client.MatchingLabels(obs.Labels)
 			opts.Raw = &metav1.ListOptions{TypeMeta: obs.Type}
-			err = gr.List(context.TODO(), opts, obs.ObjList.(runtime.Object))
+			err = reader.List(context.TODO(), opts, obs.ObjList.(runtime.Object))
 			if err == nil {
 				items, err := meta.ExtractList(obs.ObjList.(runtime.Object))
 				if err == nil {
@@ -76,7 +77,7 @@ func (gr *Reconciler) observe(observables ...resource.Observable) (*resource.Obj
 			name := obj.GetName()
 			namespace := obj.GetNamespace()
 			otype := reflect.TypeOf(obj).String()
-			err = gr.Get(context.TODO(),
+			err = reader.Get(context.TODO(),
 				types.NamespacedName{Name: name, Namespace: namespace},
 				obs.Obj.(runtime.Object))
 			if err == nil {
@@ -96,25 +97,6 @@ func (gr *Reconciler) observe(observables ...resource.Observable) (*resource.Obj
 	return returnval, nil
 }
 
-func specDiffers(o1, o2 metav1.Object) bool {
-	// Not all k8s objects have Spec
-	// example ConfigMap
-	// TODO strategic merge patch diff in generic controller loop
-	e := reflect.Indirect(reflect.ValueOf(o1)).FieldByName("Spec")
-	o := reflect.Indirect(reflect.ValueOf(o2)).FieldByName("Spec")
-	if !e.IsValid() {
-		// handling ConfigMap
-		e = reflect.Indirect(reflect.ValueOf(o1)).FieldByName("Data")
-		o = reflect.Indirect(reflect.ValueOf(o2)).FieldByName("Data")
-	}
-	if e.IsValid() && o.IsValid() {
-		if reflect.DeepEqual(e.Interface(), o.Interface()) {
-			return false
-		}
-	}
-	return true
-}
-
 // ReconcileCR is a generic function that reconciles expected and observed resources
 func (gr *Reconciler) ReconcileCR(namespacedname types.NamespacedName, handle cr.Handle) error {
 	var status interface{}
@@ -132,10 +114,27 @@ func (gr *Reconciler) ReconcileCR(namespacedname types.NamespacedName, handle cr
 			log.Printf("%s Applying defaults\n", name)
 			rsrc.ApplyDefaults()
 			components := rsrc.Components()
-			for _, component := range components {
-				if o.GetDeletionTimestamp() == nil {
-					err = gr.ReconcileComponent(name, component, status, expected)
-				} else {
+			if o.GetDeletionTimestamp() == nil {
+				for _, wave := range wavesOf(components) {
+					waveReady := true
+					var observed *resource.ObjectBag
+					for _, component := range wave {
+						observed, err = gr.ReconcileComponent(name, component, status, expected)
+						if err != nil {
+							break
+						}
+						if !component.Ready(observed) {
+							waveReady = false
+						}
+					}
+					setStatusWave(status, wave[0].Wave)
+					if err != nil || !waveReady {
+						log.Printf("%s sync-wave %d not ready, holding later waves\n", name, wave[0].Wave)
+						break
+					}
+				}
+			} else {
+				for _, component := range components {
 					err = gr.FinalizeComponent(name, component, status, expected)
 				}
 			}
@@ -208,8 +207,10 @@ func (gr *Reconciler) FinalizeComponent(crname string, c component.Component, st
 	return err
 }
 
-// ReconcileComponent is a generic function that reconciles expected and observed resources
-func (gr *Reconciler) ReconcileComponent(crname string, c component.Component, status interface{}, aggregated *resource.ObjectBag) error {
+// ReconcileComponent is a generic function that reconciles expected and observed resources.
+// It returns the observed resources it reconciled against, so callers doing
+// sync-wave gating can evaluate component.Component.Ready on them.
+func (gr *Reconciler) ReconcileComponent(crname string, c component.Component, status interface{}, aggregated *resource.ObjectBag) (*resource.ObjectBag, error) {
 	errs := []error{}
 	reconciled := []metav1.Object{}
 
@@ -247,7 +248,7 @@ func (gr *Reconciler) ReconcileComponent(crname string, c component.Component, s
 
 		log.Printf("%s  Reconciling Resources:\n", cname)
 	}
-	for _, e := range expected.Items() {
+	for _, e := range sortByResourceWave(expected.Items(), c.Wave) {
 		seen := false
 		eNamespace := e.Obj.GetNamespace()
 		eName := e.Obj.GetName()
@@ -257,13 +258,49 @@ func (gr *Reconciler) ReconcileComponent(crname string, c component.Component, s
 			if (eName == o.Obj.GetName()) &&
 				(eNamespace == o.Obj.GetNamespace()) &&
 				(eKind == reflect.TypeOf(o.Obj).String()) {
-				// rsrc is seen in both expected and observed, update it if needed
-				e.Obj.SetResourceVersion(o.Obj.GetResourceVersion())
-				if e.Lifecycle == resource.LifecycleManaged && specDiffers(e.Obj, o.Obj) && c.Differs(e.Obj, o.Obj) {
-					if err := gr.Update(context.TODO(), e.Obj.(runtime.Object).DeepCopyObject()); err != nil {
-						errs = handleErrorArr("update", eRsrcInfo, err, errs)
+				// rsrc is seen in both expected and observed, patch it if needed
+				opts := getSyncOptions(e.Obj)
+				if e.Lifecycle == resource.LifecycleManaged && c.Differs(e.Obj, o.Obj) {
+					if opts.replace {
+						// Delete+Create, not an update: e.Obj must reach the API
+						// server with no resourceVersion, or Create rejects it
+						// ("resourceVersion should not be set on objects to be
+						// created").
+						e.Obj.SetResourceVersion("")
+						if err := gr.Delete(context.TODO(), o.Obj.(runtime.Object)); err != nil {
+							errs = handleErrorArr("replace", eRsrcInfo, err, errs)
+						} else if err := stampLastApplied(e); err != nil {
+							errs = handleErrorArr("replace", eRsrcInfo, err, errs)
+						} else if err := gr.Create(context.TODO(), e.Obj.(runtime.Object)); err != nil {
+							errs = handleErrorArr("replace", eRsrcInfo, err, errs)
+						} else {
+							log.Printf("%s   replace: %s\n", cname, eRsrcInfo)
+						}
+					} else if gr.useServerSideApply(e) {
+						if err := gr.serverSideApply(context.TODO(), e.Obj.(runtime.Object), gr.fieldManager(c.Name)); err != nil {
+							errs = handleErrorArr("update", eRsrcInfo, err, errs)
+						} else {
+							log.Printf("%s   apply: %s\n", cname, eRsrcInfo)
+						}
 					} else {
-						log.Printf("%s   update: %s\n", cname, eRsrcInfo)
+						// Patch path only: stamp last-applied first, so the
+						// baseline holds no resourceVersion and stays the same
+						// across server-side writes; then stamp e.Obj with
+						// observed's resourceVersion so computePatch doesn't see
+						// a spurious diff against the object it's patching.
+						lastAppliedErr := stampLastApplied(e)
+						e.Obj.SetResourceVersion(o.Obj.GetResourceVersion())
+						if lastAppliedErr != nil {
+							errs = handleErrorArr("update", eRsrcInfo, lastAppliedErr, errs)
+						} else if patch, err := computePatch(gr.Scheme, e.Obj, o.Obj); err != nil {
+							errs = handleErrorArr("update", eRsrcInfo, err, errs)
+						} else if patch == nil {
+							log.Printf("%s   nochange: %s\n", cname, eRsrcInfo)
+						} else if err := gr.Patch(context.TODO(), o.Obj.(runtime.Object).DeepCopyObject(), patch); err != nil {
+							errs = handleErrorArr("update", eRsrcInfo, err, errs)
+						} else {
+							log.Printf("%s   update: %s\n", cname, eRsrcInfo)
+						}
 					}
 				} else {
 					log.Printf("%s   nochange: %s\n", cname, eRsrcInfo)
@@ -276,7 +313,28 @@ func (gr *Reconciler) ReconcileComponent(crname string, c component.Component, s
 		// rsrc is in expected but not in observed - create
 		if !seen {
 			if e.Lifecycle == resource.LifecycleManaged {
-				if err := gr.Create(context.TODO(), e.Obj.(runtime.Object)); err != nil {
+				var err error
+				if gr.useServerSideApply(e) {
+					err = gr.serverSideApply(context.TODO(), e.Obj.(runtime.Object), gr.fieldManager(c.Name))
+				} else {
+					// SkipDryRunOnMissingResource=false (the default) validates a
+					// missing resource against the API server's admission chain
+					// with a dry-run Create before actually creating it; the
+					// annotation opts out, e.g. when the dry run itself would fail
+					// because a CRD/webhook this resource depends on isn't
+					// registered yet.
+					if !getSyncOptions(e.Obj).skipDryRunOnMissing {
+						if dryRunErr := gr.Create(context.TODO(), e.Obj.(runtime.Object).DeepCopyObject(), client.DryRunAll); dryRunErr != nil {
+							err = fmt.Errorf("dry-run create: %s", dryRunErr.Error())
+						}
+					}
+					if err == nil {
+						if err = stampLastApplied(e); err == nil {
+							err = gr.Create(context.TODO(), e.Obj.(runtime.Object))
+						}
+					}
+				}
+				if err != nil {
 					errs = handleErrorArr("Create", cname, err, errs)
 				} else {
 					log.Printf("%s   +create: %s\n", cname, eRsrcInfo)
@@ -306,7 +364,10 @@ func (gr *Reconciler) ReconcileComponent(crname string, c component.Component, s
 		}
 		// rsrc is in observed but not in expected - delete
 		if !seen {
-			if err := gr.Delete(context.TODO(), o.Obj.(runtime.Object)); err != nil {
+			opts := getSyncOptions(o.Obj)
+			if opts.ignoreExtraneous || !opts.prune {
+				log.Printf("%s   -skip delete (%s): %s\n", cname, compareOptionsAnnotation+"/"+syncOptionsAnnotation, oRsrcInfo)
+			} else if err := gr.Delete(context.TODO(), o.Obj.(runtime.Object)); err != nil {
 				errs = handleErrorArr("delete", oRsrcInfo, err, errs)
 			} else {
 				log.Printf("%s   -delete: %s\n", cname, oRsrcInfo)
@@ -316,7 +377,7 @@ func (gr *Reconciler) ReconcileComponent(crname string, c component.Component, s
 
 	err = utilerrors.NewAggregate(errs)
 	c.UpdateComponentStatus(c.CR, status, reconciled, err)
-	return err
+	return observed, err
 }
 
 // Reconcile expected by kubebuilder
@@ -337,4 +398,6 @@ func (gr *Reconciler) Init() {
 	gr.Scheme = gr.Manager.GetScheme()
 	app.AddToScheme(&AddToSchemes)
 	AddToSchemes.AddToScheme(gr.Scheme)
+	gr.warmCaches()
+	gr.setupWatches()
 }
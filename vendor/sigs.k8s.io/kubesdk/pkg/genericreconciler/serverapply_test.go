@@ -0,0 +1,56 @@
+/*
+Copyright 2018 Google LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package genericreconciler
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// TestToApplyUnstructuredStripsServerFields covers the fields serverSideApply
+// must never send back to the API server: resourceVersion/creationTimestamp
+// (server-set) and status (not owned by the apply request).
+func TestToApplyUnstructuredStripsServerFields(t *testing.T) {
+	scheme := newPodSchemeTest(t)
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "web",
+			Namespace:         "default",
+			ResourceVersion:   "123",
+			CreationTimestamp: metav1.Now(),
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+
+	u, err := toApplyUnstructured(scheme, pod)
+	if err != nil {
+		t.Fatalf("toApplyUnstructured: %v", err)
+	}
+
+	if _, found, _ := unstructured.NestedFieldNoCopy(u.Object, "metadata", "resourceVersion"); found {
+		t.Fatalf("toApplyUnstructured() kept metadata.resourceVersion")
+	}
+	if _, found, _ := unstructured.NestedFieldNoCopy(u.Object, "metadata", "creationTimestamp"); found {
+		t.Fatalf("toApplyUnstructured() kept metadata.creationTimestamp")
+	}
+	if _, found, _ := unstructured.NestedFieldNoCopy(u.Object, "status"); found {
+		t.Fatalf("toApplyUnstructured() kept status")
+	}
+	if got := u.GetObjectKind().GroupVersionKind().Kind; got != "Pod" {
+		t.Fatalf("toApplyUnstructured() GVK kind = %q, want Pod", got)
+	}
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2018 Google LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package genericreconciler
+
+import (
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Well-known annotations that let component authors steer the reconcile loop
+// on a per-resource basis without changing Go code, borrowed from the
+// compare-options/sync-options convention popularised by GitOps engines.
+const (
+	compareOptionsAnnotation = "airflowop.k8s.io/compare-options"
+	syncOptionsAnnotation    = "airflowop.k8s.io/sync-options"
+
+	compareOptionIgnoreExtraneous = "IgnoreExtraneous"
+
+	syncOptionNoPrune             = "Prune=false"
+	syncOptionReplace             = "Replace=true"
+	syncOptionSkipDryRunOnMissing = "SkipDryRunOnMissingResource=true"
+	syncOptionServerSideApply     = "ServerSideApply=true"
+)
+
+// syncOptions is the parsed form of the sync-options/compare-options
+// annotations on a single resource.
+type syncOptions struct {
+	ignoreExtraneous    bool
+	prune               bool
+	replace             bool
+	skipDryRunOnMissing bool
+	serverSideApply     bool
+}
+
+func parseOptionsList(csv string) []string {
+	var out []string
+	for _, o := range strings.Split(csv, ",") {
+		o = strings.TrimSpace(o)
+		if o != "" {
+			out = append(out, o)
+		}
+	}
+	return out
+}
+
+// getSyncOptions reads the sync-options/compare-options annotations off o,
+// defaulting Prune to true (the existing delete-observed-minus-expected
+// behavior) when the annotation is absent.
+func getSyncOptions(o metav1.Object) syncOptions {
+	opts := syncOptions{prune: true}
+	a := o.GetAnnotations()
+	if a == nil {
+		return opts
+	}
+	for _, opt := range parseOptionsList(a[compareOptionsAnnotation]) {
+		if opt == compareOptionIgnoreExtraneous {
+			opts.ignoreExtraneous = true
+		}
+	}
+	for _, opt := range parseOptionsList(a[syncOptionsAnnotation]) {
+		switch opt {
+		case syncOptionNoPrune:
+			opts.prune = false
+		case syncOptionReplace:
+			opts.replace = true
+		case syncOptionSkipDryRunOnMissing:
+			opts.skipDryRunOnMissing = true
+		case syncOptionServerSideApply:
+			opts.serverSideApply = true
+		}
+	}
+	return opts
+}
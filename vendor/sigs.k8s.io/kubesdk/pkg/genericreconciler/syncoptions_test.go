@@ -0,0 +1,94 @@
+/*
+Copyright 2018 Google LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package genericreconciler
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func configMapWithOptions(compareOptions, syncOptionsCSV string) metav1.Object {
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "cm"}}
+	annotations := map[string]string{}
+	if compareOptions != "" {
+		annotations[compareOptionsAnnotation] = compareOptions
+	}
+	if syncOptionsCSV != "" {
+		annotations[syncOptionsAnnotation] = syncOptionsCSV
+	}
+	if len(annotations) > 0 {
+		cm.Annotations = annotations
+	}
+	return cm
+}
+
+func TestGetSyncOptions(t *testing.T) {
+	tests := []struct {
+		name           string
+		compareOptions string
+		syncOptionsCSV string
+		want           syncOptions
+	}{
+		{
+			name: "no annotations defaults Prune to true",
+			want: syncOptions{prune: true},
+		},
+		{
+			name:           "IgnoreExtraneous",
+			compareOptions: compareOptionIgnoreExtraneous,
+			want:           syncOptions{prune: true, ignoreExtraneous: true},
+		},
+		{
+			name:           "Prune=false",
+			syncOptionsCSV: syncOptionNoPrune,
+			want:           syncOptions{prune: false},
+		},
+		{
+			name:           "Replace=true",
+			syncOptionsCSV: syncOptionReplace,
+			want:           syncOptions{prune: true, replace: true},
+		},
+		{
+			name:           "SkipDryRunOnMissingResource=true",
+			syncOptionsCSV: syncOptionSkipDryRunOnMissing,
+			want:           syncOptions{prune: true, skipDryRunOnMissing: true},
+		},
+		{
+			name:           "ServerSideApply=true",
+			syncOptionsCSV: syncOptionServerSideApply,
+			want:           syncOptions{prune: true, serverSideApply: true},
+		},
+		{
+			name:           "multiple sync-options combine, whitespace tolerated",
+			syncOptionsCSV: syncOptionNoPrune + ", " + syncOptionServerSideApply,
+			want:           syncOptions{prune: false, serverSideApply: true},
+		},
+		{
+			name:           "unknown option is ignored",
+			syncOptionsCSV: "Bogus=true",
+			want:           syncOptions{prune: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := getSyncOptions(configMapWithOptions(tt.compareOptions, tt.syncOptionsCSV))
+			if got != tt.want {
+				t.Fatalf("getSyncOptions() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
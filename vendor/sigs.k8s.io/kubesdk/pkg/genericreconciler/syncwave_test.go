@@ -0,0 +1,152 @@
+/*
+Copyright 2018 Google LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package genericreconciler
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/kubesdk/pkg/component"
+	"sigs.k8s.io/kubesdk/pkg/resource"
+)
+
+// wavesOfNames is a small helper that turns wavesOf's grouped output into
+// the component names per wave, so test cases can assert on plain strings
+// rather than reaching into component.Component.
+func wavesOfNames(components []component.Component) [][]string {
+	var out [][]string
+	for _, wave := range wavesOf(components) {
+		var names []string
+		for _, c := range wave {
+			names = append(names, c.Name)
+		}
+		out = append(out, names)
+	}
+	return out
+}
+
+func TestWavesOf(t *testing.T) {
+	tests := []struct {
+		name       string
+		components []component.Component
+		want       [][]string
+	}{
+		{
+			name:       "empty",
+			components: nil,
+			want:       nil,
+		},
+		{
+			name: "single wave, insertion order preserved",
+			components: []component.Component{
+				{Name: "ui", Wave: 0},
+				{Name: "api", Wave: 0},
+			},
+			want: [][]string{{"ui", "api"}},
+		},
+		{
+			name: "waves sorted ascending regardless of input order",
+			components: []component.Component{
+				{Name: "ui", Wave: 3},
+				{Name: "mysql", Wave: 0},
+				{Name: "sqlproxy", Wave: 2},
+				{Name: "nfs", Wave: 1},
+			},
+			want: [][]string{{"mysql"}, {"nfs"}, {"sqlproxy"}, {"ui"}},
+		},
+		{
+			name: "negative and repeated waves group together",
+			components: []component.Component{
+				{Name: "pre", Wave: -1},
+				{Name: "mysql", Wave: 0},
+				{Name: "sqlproxy", Wave: 0},
+			},
+			want: [][]string{{"pre"}, {"mysql", "sqlproxy"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := wavesOfNames(tt.components)
+			if len(got) != len(tt.want) {
+				t.Fatalf("wavesOf() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if len(got[i]) != len(tt.want[i]) {
+					t.Fatalf("wavesOf() = %v, want %v", got, tt.want)
+				}
+				for j := range got[i] {
+					if got[i][j] != tt.want[i][j] {
+						t.Fatalf("wavesOf() = %v, want %v", got, tt.want)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestSetStatusWave(t *testing.T) {
+	type statusWithWave struct {
+		Wave int
+	}
+	type statusWithoutWave struct {
+		Ready bool
+	}
+
+	t.Run("sets an int Wave field", func(t *testing.T) {
+		status := &statusWithWave{}
+		setStatusWave(status, 2)
+		if status.Wave != 2 {
+			t.Fatalf("status.Wave = %d, want 2", status.Wave)
+		}
+	})
+
+	t.Run("no-ops when the status type has no Wave field", func(t *testing.T) {
+		status := &statusWithoutWave{Ready: true}
+		setStatusWave(status, 2)
+		if !status.Ready {
+			t.Fatalf("setStatusWave mutated an unrelated field")
+		}
+	})
+}
+
+func namedConfigMap(name string, wave string) resource.Object {
+	cm := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: name}}
+	if wave != "" {
+		cm.Annotations = map[string]string{resourceWaveAnnotation: wave}
+	}
+	return resource.Object{Obj: cm}
+}
+
+func TestSortByResourceWave(t *testing.T) {
+	items := []resource.Object{
+		namedConfigMap("default", ""),
+		namedConfigMap("early", "-1"),
+		namedConfigMap("late", "5"),
+		namedConfigMap("malformed", "not-a-number"),
+	}
+
+	got := sortByResourceWave(items, 2)
+
+	want := []string{"early", "default", "malformed", "late"}
+	if len(got) != len(want) {
+		t.Fatalf("sortByResourceWave() = %d items, want %d", len(got), len(want))
+	}
+	for i, name := range want {
+		if got[i].Obj.GetName() != name {
+			t.Fatalf("sortByResourceWave()[%d] = %q, want %q", i, got[i].Obj.GetName(), name)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2018 Google LLC
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+    http://www.apache.org/licenses/LICENSE-2.0
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package resource provides the common currency genericreconciler and
+// component authors exchange: a single managed-or-referred object plus the
+// bag of such objects a component expects or observes.
+package resource
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Lifecycle describes how genericreconciler should treat a resource.Object
+// relative to its owning component.
+type Lifecycle string
+
+const (
+	// LifecycleManaged resources are created, updated and deleted by the
+	// reconciler to match what the component expects.
+	LifecycleManaged Lifecycle = "managed"
+	// LifecycleReferred resources are expected to already exist, owned by
+	// something else; a missing one is surfaced as a reconcile error instead
+	// of being created.
+	LifecycleReferred Lifecycle = "referred"
+)
+
+// Object pairs a concrete Kubernetes object with how genericreconciler
+// should treat it.
+type Object struct {
+	Lifecycle Lifecycle
+	Obj       metav1.Object
+
+	// SkipLastApplied opts Obj out of the kubectl.kubernetes.io/last-applied-
+	// configuration-style annotation genericreconciler stamps on every
+	// successful create/update for its three-way diff. Component authors set
+	// this for types where echoing the full payload back onto the object is
+	// undesirable, e.g. a Secret.
+	SkipLastApplied bool
+}
+
+// Observable describes a kind of resource a component wants genericreconciler
+// to fetch on its behalf: either a single named object (Obj) or a labeled
+// list (ObjList/Labels).
+type Observable struct {
+	Obj     interface{}
+	ObjList interface{}
+	Labels  map[string]string
+	Type    metav1.TypeMeta
+}
+
+// ObjectBag is an unordered collection of Objects, e.g. the expected or
+// observed resources of a component.
+type ObjectBag struct {
+	items []Object
+}
+
+// Add appends the given objects to the bag.
+func (b *ObjectBag) Add(objs ...Object) {
+	b.items = append(b.items, objs...)
+}
+
+// Items returns every Object currently in the bag.
+func (b *ObjectBag) Items() []Object {
+	return b.items
+}